@@ -0,0 +1,141 @@
+// Package alerts evaluates a stream of battery samples against
+// user-configured thresholds (see package config) and fires shell
+// commands, desktop notifications, or built-in sounds on transitions
+// into a band - borrowing the idea from xmobar's Batt plugin
+// (low/medium/high thresholds and an onLowAction hook).
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/aezizhu/chargetop/battery"
+	"github.com/aezizhu/chargetop/config"
+)
+
+// hookTimeout bounds how long a single Fire call (shell command,
+// notification, or sound) is allowed to run. Fire is meant to be
+// called off the UI goroutine (see Evaluator.Sample's doc comment),
+// but a user-configured Action.Command that hangs forever would still
+// leak a goroutine without this.
+const hookTimeout = 5 * time.Second
+
+// Evaluator watches a stream of battery.BatteryInfo samples and
+// reports each Band newly entered, debounced by the configured
+// ActionCooldown so a reading that stays below a threshold doesn't
+// re-fire the action every tick.
+type Evaluator struct {
+	bands    []config.Band
+	cooldown time.Duration
+
+	inBand   map[string]bool
+	lastFire map[string]time.Time
+	now      func() time.Time
+
+	// Notify and Exec perform the actual side effects; they're fields
+	// (rather than direct calls) so tests can substitute fakes.
+	Notify func(ctx context.Context, title, body string) error
+	Exec   func(ctx context.Context, command string) error
+}
+
+// New builds an Evaluator from cfg.
+func New(cfg config.AlertsConfig) *Evaluator {
+	return &Evaluator{
+		bands:    cfg.Bands,
+		cooldown: cfg.ActionCooldown.Duration,
+		inBand:   make(map[string]bool),
+		lastFire: make(map[string]time.Time),
+		now:      time.Now,
+		Notify:   notify,
+		Exec:     execCommand,
+	}
+}
+
+// Sample evaluates one BatteryInfo reading against every configured
+// band and returns the bands to fire: every band newly entered, plus
+// any band still active whose cooldown since its last firing has
+// passed. Bands that are no longer active are marked as left, so
+// re-entering one fires at once. It only updates
+// in-memory state, so it's safe to call directly from Bubble Tea's
+// Update; callers should pass the result to Fire from a tea.Cmd rather
+// than firing inline, since a Band's Action can shell out and block.
+func (e *Evaluator) Sample(info battery.BatteryInfo) []config.Band {
+	var fired []config.Band
+	for _, b := range e.bands {
+		active := bandActive(b, info.Percent)
+		was := e.inBand[b.Name]
+		e.inBand[b.Name] = active
+
+		if !active {
+			continue
+		}
+		if was && e.now().Sub(e.lastFire[b.Name]) < e.cooldown {
+			continue // still inside the band and inside the cooldown window
+		}
+		e.lastFire[b.Name] = e.now()
+		fired = append(fired, b)
+	}
+	return fired
+}
+
+func bandActive(b config.Band, percent int) bool {
+	if b.Direction == "above" {
+		return percent >= b.Threshold
+	}
+	return percent <= b.Threshold
+}
+
+// Fire runs b's Action (command, notification, sound), each bounded
+// by hookTimeout so a hung hook can't block its caller indefinitely.
+// Intended to be called from a tea.Cmd, not from Update directly.
+func (e *Evaluator) Fire(ctx context.Context, b config.Band) {
+	a := b.Action
+	if a.Command != "" {
+		cctx, cancel := context.WithTimeout(ctx, hookTimeout)
+		_ = e.Exec(cctx, a.Command)
+		cancel()
+	}
+	if a.Notify != "" {
+		cctx, cancel := context.WithTimeout(ctx, hookTimeout)
+		_ = e.Notify(cctx, fmt.Sprintf("chargetop: %s", b.Name), a.Notify)
+		cancel()
+	}
+	if a.Sound != "" {
+		cctx, cancel := context.WithTimeout(ctx, hookTimeout)
+		_ = playSound(cctx, a.Sound)
+		cancel()
+	}
+}
+
+func execCommand(ctx context.Context, command string) error {
+	return exec.CommandContext(ctx, "sh", "-c", command).Run()
+}
+
+func notify(ctx context.Context, title, body string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("terminal-notifier"); err == nil {
+			return exec.CommandContext(ctx, "terminal-notifier", "-title", title, "-message", body).Run()
+		}
+		script := fmt.Sprintf(`display notification %q with title %q`, body, title)
+		return exec.CommandContext(ctx, "osascript", "-e", script).Run()
+	case "linux":
+		return exec.CommandContext(ctx, "notify-send", title, body).Run()
+	default:
+		return fmt.Errorf("alerts: desktop notifications unsupported on %s", runtime.GOOS)
+	}
+}
+
+func playSound(ctx context.Context, name string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.CommandContext(ctx, "afplay", fmt.Sprintf("/System/Library/Sounds/%s.aiff", name)).Run()
+	case "linux":
+		return exec.CommandContext(ctx, "paplay", fmt.Sprintf("/usr/share/sounds/freedesktop/stereo/%s.oga", name)).Run()
+	default:
+		return fmt.Errorf("alerts: built-in sounds unsupported on %s", runtime.GOOS)
+	}
+}
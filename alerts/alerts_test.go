@@ -0,0 +1,122 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aezizhu/chargetop/battery"
+	"github.com/aezizhu/chargetop/config"
+)
+
+func testConfig(cooldown time.Duration) config.AlertsConfig {
+	return config.AlertsConfig{
+		ActionCooldown: config.Duration{Duration: cooldown},
+		Bands: []config.Band{
+			{Name: "low", Threshold: 15, Direction: "below", Action: config.Action{Notify: "low battery"}},
+		},
+	}
+}
+
+func names(bands []config.Band) []string {
+	out := make([]string, len(bands))
+	for i, b := range bands {
+		out[i] = b.Name
+	}
+	return out
+}
+
+func TestSampleFiresOnTransitionIntoBand(t *testing.T) {
+	e := New(testConfig(10 * time.Minute))
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+
+	if fired := e.Sample(battery.BatteryInfo{Percent: 50}); len(fired) != 0 {
+		t.Fatalf("above threshold: fired = %v, want none", names(fired))
+	}
+	fired := e.Sample(battery.BatteryInfo{Percent: 10})
+	if got := names(fired); len(got) != 1 || got[0] != "low" {
+		t.Fatalf("transition into band: fired = %v, want [low]", got)
+	}
+}
+
+func TestSampleSuppressesDuringCooldown(t *testing.T) {
+	e := New(testConfig(10 * time.Minute))
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+
+	if fired := e.Sample(battery.BatteryInfo{Percent: 10}); len(names(fired)) != 1 {
+		t.Fatalf("first sample in band: fired = %v, want [low]", names(fired))
+	}
+
+	now = now.Add(time.Minute) // still inside the 10m cooldown
+	if fired := e.Sample(battery.BatteryInfo{Percent: 9}); len(fired) != 0 {
+		t.Fatalf("still in band, inside cooldown: fired = %v, want none", names(fired))
+	}
+}
+
+func TestSampleRefiresAfterCooldownExpires(t *testing.T) {
+	e := New(testConfig(10 * time.Minute))
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+
+	if fired := e.Sample(battery.BatteryInfo{Percent: 10}); len(names(fired)) != 1 {
+		t.Fatalf("first sample in band: fired = %v, want [low]", names(fired))
+	}
+
+	now = now.Add(11 * time.Minute) // past the 10m cooldown, still in band
+	fired := e.Sample(battery.BatteryInfo{Percent: 8})
+	if got := names(fired); len(got) != 1 || got[0] != "low" {
+		t.Fatalf("after cooldown expires, still in band: fired = %v, want [low]", got)
+	}
+}
+
+func TestSampleClearsStateOnExitingBand(t *testing.T) {
+	e := New(testConfig(10 * time.Minute))
+	now := time.Unix(0, 0)
+	e.now = func() time.Time { return now }
+
+	e.Sample(battery.BatteryInfo{Percent: 10}) // enters the band
+
+	now = now.Add(time.Minute)
+	e.Sample(battery.BatteryInfo{Percent: 50}) // leaves the band, well inside the old cooldown window
+
+	now = now.Add(time.Minute)
+	fired := e.Sample(battery.BatteryInfo{Percent: 10}) // re-enters immediately
+	if got := names(fired); len(got) != 1 || got[0] != "low" {
+		t.Fatalf("re-entering band after leaving it: fired = %v, want [low]", got)
+	}
+}
+
+func TestFireRunsConfiguredActionsWithBoundedContext(t *testing.T) {
+	e := New(testConfig(10 * time.Minute))
+
+	var gotCommand, gotTitle, gotBody string
+	e.Exec = func(ctx context.Context, command string) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("Exec: ctx has no deadline, want one bounded by hookTimeout")
+		}
+		gotCommand = command
+		return nil
+	}
+	e.Notify = func(ctx context.Context, title, body string) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("Notify: ctx has no deadline, want one bounded by hookTimeout")
+		}
+		gotTitle, gotBody = title, body
+		return nil
+	}
+
+	b := config.Band{Name: "low", Action: config.Action{Command: "echo hi", Notify: "low battery"}}
+	e.Fire(context.Background(), b)
+
+	if gotCommand != "echo hi" {
+		t.Errorf("Exec command = %q, want %q", gotCommand, "echo hi")
+	}
+	if gotBody != "low battery" {
+		t.Errorf("Notify body = %q, want %q", gotBody, "low battery")
+	}
+	if gotTitle == "" {
+		t.Error("Notify title is empty, want it to mention the band")
+	}
+}
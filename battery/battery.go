@@ -1,14 +1,18 @@
 package battery
 
 import (
-	"bytes"
-	"fmt"
-	"os/exec"
-	"regexp"
-	"strconv"
+	"context"
+	"errors"
 )
 
+// BatteryInfo describes one physical battery (or UPS) at a point in time.
 type BatteryInfo struct {
+	// ID is a stable identifier for this battery within a System, e.g.
+	// "BAT0", "BAT1", or "AppleSmartBattery". Providers that only ever
+	// see one battery still set it, so callers can treat the
+	// single-battery and multi-battery cases uniformly.
+	ID string
+
 	Percent    int
 	Status     string
 	Remaining  string
@@ -22,125 +26,115 @@ type BatteryInfo struct {
 	Health      string // e.g. "95%" if calculated
 	Wattage     int
 	Serial      string
-}
-
-func GetBatteryInfo() (BatteryInfo, error) {
-	info := BatteryInfo{
-		Status:    "Unknown",
-		Remaining: "Calculating...",
-	}
-
-	// 1. Get Basic Info from pmset (it has the best status/remaining logic)
-	// We could parse ioreg for everything, but pmset's time remaining is standard.
-	// Actually, let's parse ioreg for *everything* to be faster and consistent.
-	// ioreg -r -n AppleSmartBattery
-
-	cmd := exec.Command("ioreg", "-r", "-n", "AppleSmartBattery")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
-	if err != nil {
-		return info, err
-	}
-	output := out.String()
 
-	// Parse Fields
-
-	// State of Charge
-	// "CurrentCapacity" = 15
-	// "MaxCapacity" = 100
-	// Make sure to match specific keys, as MaxCapacity might appear multiple times.
-	// Using generic "Key" = Value regex
+	// DesignCapacity, when known, weights this battery's contribution
+	// to System.Percent. 0 means "unknown" and falls back to an
+	// unweighted average.
+	DesignCapacity int
+}
 
-	currentCap := getInt(output, `\"CurrentCapacity\"\s*=\s*(\d+)`)
-	maxCap := getInt(output, `\"MaxCapacity\"\s*=\s*(\d+)`)
+// System aggregates every BatteryInfo a Provider reports for a
+// machine - most laptops have exactly one, but some Linux/BSD laptops
+// and external UPS devices report more.
+type System struct {
+	Batteries []BatteryInfo
+
+	// Percent is the design-capacity-weighted average percent across
+	// every battery (falling back to an unweighted average when design
+	// capacities aren't reported).
+	Percent int
+	// IsCharging is true if any battery in Batteries is charging.
+	IsCharging bool
+}
 
-	if maxCap > 0 {
-		info.Percent = (currentCap * 100) / maxCap
-		// Overwrite with pmset check if needed, but this is raw controller data
-		// Some people prefer "AppleRawCurrentCapacity" vs "AppleRawMaxCapacity"
-	}
+// NewSystem computes a System's aggregate fields from its Batteries.
+func NewSystem(batteries []BatteryInfo) System {
+	sys := System{Batteries: batteries}
 
-	// Use regex to find IsCharging
-	// "IsCharging" = Yes
-	if getString(output, `\"IsCharging\"\s*=\s*(Yes|No)`) == "Yes" {
-		info.IsCharging = true
-		info.Status = "Charging"
-	} else {
-		info.IsCharging = false
-		info.Status = "Discharging"
-		if getString(output, `\"FullyCharged\"\s*=\s*(Yes)`) == "Yes" {
-			info.Status = "Charged"
+	totalWeight, weightedPercent := 0, 0
+	for _, b := range batteries {
+		weight := b.DesignCapacity
+		if weight == 0 {
+			weight = 1 // unweighted fallback when design capacity isn't known
 		}
-	}
+		weightedPercent += b.Percent * weight
+		totalWeight += weight
 
-	// Time Remaining
-	// "TimeRemaining" = 177 (minutes)
-	tr := getInt(output, `\"TimeRemaining\"\s*=\s*(\d+)`)
-	if tr < 65535 {
-		h := tr / 60
-		m := tr % 60
-		info.Remaining = fmt.Sprintf("%d:%02d remaining", h, m)
-	} else {
-		info.Remaining = "Calculating..." // 65535 often means calculating
-		if info.Status == "Charged" {
-			info.Remaining = ""
+		if b.IsCharging {
+			sys.IsCharging = true
 		}
 	}
+	if totalWeight > 0 {
+		sys.Percent = weightedPercent / totalWeight
+	}
+	return sys
+}
 
-	// Temperature
-	// "Temperature" = 3040 (centidegrees)
-	temp := getInt(output, `\"Temperature\"\s*=\s*(\d+)`)
-	if temp > 0 {
-		info.Temperature = float64(temp) / 100.0
+// Aggregate returns a synthetic BatteryInfo representing the whole
+// System: Percent and IsCharging come from the weighted aggregate,
+// while the remaining fields (temperature, serial, etc.) are taken
+// from the first battery. For the common single-battery case this is
+// identical to Batteries[0].
+func (s System) Aggregate() BatteryInfo {
+	if len(s.Batteries) == 0 {
+		return BatteryInfo{Status: "Unknown", Percent: s.Percent, IsCharging: s.IsCharging}
 	}
+	info := s.Batteries[0]
+	info.Percent = s.Percent
+	info.IsCharging = s.IsCharging
+	return info
+}
 
-	// CycleCount
-	// "CycleCount" = 193
-	info.CycleCount = getInt(output, `\"CycleCount\"\s*=\s*(\d+)`)
+// Provider is a platform-specific source of battery telemetry. Each
+// supported OS registers its implementation from an init() function
+// guarded by a build tag (see battery_darwin.go, battery_linux.go,
+// battery_freebsd.go and battery_windows.go), so a given binary only
+// pulls in the code path for the platform it's built for.
+type Provider interface {
+	// Name identifies the provider, e.g. "darwin-ioreg" or "linux-sysfs".
+	Name() string
+	// Read samples the current battery state.
+	Read(ctx context.Context) (System, error)
+}
 
-	// Watts
-	// "Watts"=60 (inside AdapterDetails)
-	info.Wattage = getInt(output, `\"Watts\"=(\d+)`)
+var active Provider
 
-	// Serial
-	// "Serial" = "F8..."
-	info.Serial = getString(output, `\"Serial\"\s*=\s*\"([^\"]+)\"`)
+// Register installs p as the active Provider. Platform files in this
+// package call Register from their init(); callers (tests, alternate
+// frontends) can also call it directly to inject a MockProvider.
+func Register(p Provider) {
+	active = p
+}
 
-	// Design Cap for Health Calcs
-	// "DesignCapacity" = 8579
-	designCap := getInt(output, `\"DesignCapacity\"\s*=\s*(\d+)`)
-	appleRawMax := getInt(output, `\"AppleRawMaxCapacity\"\s*=\s*(\d+)`)
+// Current returns the currently registered Provider, or nil if no
+// platform implementation was compiled in and none has been
+// registered manually.
+func Current() Provider {
+	return active
+}
 
-	if designCap > 0 && appleRawMax > 0 {
-		healthPct := (float64(appleRawMax) / float64(designCap)) * 100
-		info.Health = fmt.Sprintf("%.0f%%", healthPct)
+// GetSystem samples the active Provider. It's kept as a package-level
+// function so existing callers don't need to know about the Provider
+// interface at all.
+func GetSystem() (System, error) {
+	if active == nil {
+		return System{}, errors.New("battery: no provider registered for this platform")
 	}
-
-	info.MaxCapacity = maxCap // This is relative max capacity (wear info is mostly in AppleRawMax vs Design)
-
-	// Condition (Hard to map exactly without system_profiler strings, but we can infer)
-	// Or just leave blank if we rely on system_profiler.
-	// Let's stick to "Health" % which is more useful.
-
-	return info, nil
+	return active.Read(context.Background())
 }
 
-func getInt(text string, pattern string) int {
-	re := regexp.MustCompile(pattern)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		val, _ := strconv.Atoi(matches[1])
-		return val
-	}
-	return 0
+// MockProvider is a Provider that returns a fixed System/error on
+// every Read, useful for exercising callers (the TUI, alerts, history)
+// without real hardware:
+//
+//	battery.Register(battery.MockProvider{System: battery.NewSystem([]battery.BatteryInfo{{Percent: 42}})})
+type MockProvider struct {
+	System System
+	Err    error
 }
 
-func getString(text string, pattern string) string {
-	re := regexp.MustCompile(pattern)
-	matches := re.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
+func (m MockProvider) Name() string { return "mock" }
+
+func (m MockProvider) Read(ctx context.Context) (System, error) {
+	return m.System, m.Err
 }
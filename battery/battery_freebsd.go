@@ -0,0 +1,67 @@
+//go:build freebsd
+
+package battery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// FreeBSDProvider reads battery state from the ACPI sysctl tree
+// (hw.acpi.battery.*, hw.acpi.acline), mirroring what `apm`/`acpiconf`
+// show on the command line.
+type FreeBSDProvider struct{}
+
+func NewFreeBSDProvider() *FreeBSDProvider { return &FreeBSDProvider{} }
+
+func (p *FreeBSDProvider) Name() string { return "freebsd-sysctl" }
+
+func (p *FreeBSDProvider) Read(ctx context.Context) (System, error) {
+	info := BatteryInfo{ID: "battery0"}
+
+	life, err := sysctlInt(ctx, "hw.acpi.battery.life")
+	if err != nil {
+		return System{}, fmt.Errorf("battery: %w", err)
+	}
+	info.Percent = life
+
+	// hw.acpi.battery.state: 1=discharging, 2=charging, 7=full/idle.
+	switch state, _ := sysctlInt(ctx, "hw.acpi.battery.state"); state {
+	case 1:
+		info.Status = "Discharging"
+	case 2:
+		info.Status = "Charging"
+		info.IsCharging = true
+	default:
+		info.Status = "Charged"
+	}
+
+	// hw.acpi.acline: 1 means the AC adapter is plugged in.
+	if online, err := sysctlInt(ctx, "hw.acpi.acline"); err == nil && online == 1 {
+		info.IsCharging = true
+	}
+
+	if mins, err := sysctlInt(ctx, "hw.acpi.battery.time"); err == nil && mins >= 0 {
+		info.Remaining = fmt.Sprintf("%d:%02d remaining", mins/60, mins%60)
+	}
+
+	return NewSystem([]BatteryInfo{info}), nil
+}
+
+func sysctlInt(ctx context.Context, name string) (int, error) {
+	cmd := exec.CommandContext(ctx, "sysctl", "-n", name)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out.String()))
+}
+
+func init() {
+	Register(NewFreeBSDProvider())
+}
@@ -0,0 +1,134 @@
+//go:build linux
+
+package battery
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// LinuxProvider reads battery and AC-adapter state from sysfs
+// (/sys/class/power_supply/BAT* and /sys/class/power_supply/A{C,DP}*),
+// the same attributes the barista and xmobar battery modules read.
+//
+// FS is an io/fs.FS rather than a concrete path so tests can supply an
+// in-memory filesystem (fstest.MapFS) instead of touching /sys.
+type LinuxProvider struct {
+	FS   fs.FS
+	Root string // power_supply directory within FS, e.g. "sys/class/power_supply"
+}
+
+// NewLinuxProvider returns a LinuxProvider rooted at the real /sys.
+func NewLinuxProvider() *LinuxProvider {
+	return &LinuxProvider{FS: os.DirFS("/"), Root: "sys/class/power_supply"}
+}
+
+func (p *LinuxProvider) Name() string { return "linux-sysfs" }
+
+func (p *LinuxProvider) Read(ctx context.Context) (System, error) {
+	entries, err := fs.ReadDir(p.FS, p.Root)
+	if err != nil {
+		return System{}, fmt.Errorf("battery: reading %s: %w", p.Root, err)
+	}
+
+	var batteries []BatteryInfo
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "BAT") {
+			continue
+		}
+		info := BatteryInfo{ID: e.Name()}
+		p.readBattery(path.Join(p.Root, e.Name()), &info)
+		batteries = append(batteries, info)
+	}
+	if len(batteries) == 0 {
+		return System{}, fmt.Errorf("battery: no BAT* device found under %s", p.Root)
+	}
+
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "AC") && !strings.HasPrefix(e.Name(), "ADP") {
+			continue
+		}
+		dir := path.Join(p.Root, e.Name())
+		for i := range batteries {
+			p.readAC(dir, &batteries[i])
+		}
+		break
+	}
+
+	return NewSystem(batteries), nil
+}
+
+func (p *LinuxProvider) readBattery(dir string, info *BatteryInfo) {
+	info.Status = p.readString(dir, "status")
+	info.IsCharging = info.Status == "Charging"
+
+	now := p.readInt(dir, "charge_now")
+	full := p.readInt(dir, "charge_full")
+	designFull := p.readInt(dir, "charge_full_design")
+	if full == 0 {
+		// Drivers without charge_* (µAh) report energy_* (µWh) instead.
+		now = p.readInt(dir, "energy_now")
+		full = p.readInt(dir, "energy_full")
+		designFull = p.readInt(dir, "energy_full_design")
+	}
+
+	if capacity := p.readInt(dir, "capacity"); capacity > 0 {
+		info.Percent = capacity
+	} else if full > 0 {
+		info.Percent = now * 100 / full
+	}
+
+	info.MaxCapacity = full
+	info.DesignCapacity = designFull
+	if designFull > 0 && full > 0 {
+		info.Health = fmt.Sprintf("%.0f%%", float64(full)/float64(designFull)*100)
+	}
+
+	info.CycleCount = p.readInt(dir, "cycle_count")
+
+	// "temp" is reported in tenths of a degree Celsius.
+	if milliC := p.readInt(dir, "temp"); milliC != 0 {
+		info.Temperature = float64(milliC) / 10.0
+	}
+
+	info.Serial = p.readString(dir, "serial_number")
+}
+
+func (p *LinuxProvider) readAC(dir string, info *BatteryInfo) {
+	if p.readInt(dir, "online") == 1 {
+		info.IsCharging = true
+	}
+
+	voltageUV := p.readInt(dir, "voltage_now")
+	currentUA := p.readInt(dir, "current_now")
+	if voltageUV > 0 && currentUA > 0 {
+		watts := (float64(voltageUV) / 1e6) * (float64(currentUA) / 1e6)
+		info.Wattage = int(watts + 0.5)
+	}
+}
+
+func (p *LinuxProvider) readString(dir, attr string) string {
+	b, err := fs.ReadFile(p.FS, path.Join(dir, attr))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+func (p *LinuxProvider) readInt(dir, attr string) int {
+	s := p.readString(dir, attr)
+	if s == "" {
+		return 0
+	}
+	v, _ := strconv.Atoi(s)
+	return v
+}
+
+func init() {
+	Register(NewLinuxProvider())
+}
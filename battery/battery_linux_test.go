@@ -0,0 +1,101 @@
+//go:build linux
+
+package battery
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func mapFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for path, data := range files {
+		fsys[path] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return fsys
+}
+
+func TestLinuxProviderChargeAttrs(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"sys/class/power_supply/BAT0/status":             "Discharging",
+		"sys/class/power_supply/BAT0/capacity":           "73",
+		"sys/class/power_supply/BAT0/charge_now":         "5000000",
+		"sys/class/power_supply/BAT0/charge_full":        "6000000",
+		"sys/class/power_supply/BAT0/charge_full_design": "6500000",
+		"sys/class/power_supply/BAT0/cycle_count":        "42",
+		"sys/class/power_supply/BAT0/temp":               "305",
+		"sys/class/power_supply/BAT0/serial_number":      "ABC123",
+		"sys/class/power_supply/AC/online":               "1",
+		"sys/class/power_supply/AC/voltage_now":          "20000000",
+		"sys/class/power_supply/AC/current_now":          "2000000",
+	})
+	p := &LinuxProvider{FS: fsys, Root: "sys/class/power_supply"}
+
+	sys, err := p.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(sys.Batteries) != 1 {
+		t.Fatalf("want 1 battery, got %d", len(sys.Batteries))
+	}
+	b := sys.Batteries[0]
+	if b.ID != "BAT0" {
+		t.Errorf("ID = %q, want BAT0", b.ID)
+	}
+	if b.Percent != 73 {
+		t.Errorf("Percent = %d, want 73 (from capacity)", b.Percent)
+	}
+	if !b.IsCharging {
+		t.Error("IsCharging = false, want true (AC online)")
+	}
+	if b.MaxCapacity != 6000000 || b.DesignCapacity != 6500000 {
+		t.Errorf("MaxCapacity/DesignCapacity = %d/%d, want 6000000/6500000", b.MaxCapacity, b.DesignCapacity)
+	}
+	if b.CycleCount != 42 {
+		t.Errorf("CycleCount = %d, want 42", b.CycleCount)
+	}
+	if b.Temperature != 30.5 {
+		t.Errorf("Temperature = %v, want 30.5", b.Temperature)
+	}
+	if b.Wattage != 40 {
+		t.Errorf("Wattage = %d, want 40", b.Wattage)
+	}
+}
+
+func TestLinuxProviderEnergyFallback(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"sys/class/power_supply/BAT0/status":             "Charging",
+		"sys/class/power_supply/BAT0/energy_now":         "30000000",
+		"sys/class/power_supply/BAT0/energy_full":        "60000000",
+		"sys/class/power_supply/BAT0/energy_full_design": "65000000",
+	})
+	p := &LinuxProvider{FS: fsys, Root: "sys/class/power_supply"}
+
+	sys, err := p.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	b := sys.Batteries[0]
+	if b.Percent != 50 {
+		t.Errorf("Percent = %d, want 50 (charge_* missing, fall back to energy_*)", b.Percent)
+	}
+	if b.MaxCapacity != 60000000 || b.DesignCapacity != 65000000 {
+		t.Errorf("MaxCapacity/DesignCapacity = %d/%d, want energy_* values", b.MaxCapacity, b.DesignCapacity)
+	}
+	if !b.IsCharging {
+		t.Error("IsCharging = false, want true (status=Charging)")
+	}
+}
+
+func TestLinuxProviderNoBattery(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"sys/class/power_supply/AC/online": "1",
+	})
+	p := &LinuxProvider{FS: fsys, Root: "sys/class/power_supply"}
+
+	_, err := p.Read(context.Background())
+	if err == nil {
+		t.Fatal("Read: want error when no BAT* device is present, got nil")
+	}
+}
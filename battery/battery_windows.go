@@ -0,0 +1,85 @@
+//go:build windows
+
+package battery
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// systemPowerStatus mirrors the Win32 SYSTEM_POWER_STATUS struct.
+// x/sys/windows doesn't wrap GetSystemPowerStatus (it's kernel32, not
+// one of the syscalls that package covers), so we declare the struct
+// and call the DLL ourselves - the same approach distatus/battery
+// uses.
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+var (
+	kernel32               = windows.NewLazySystemDLL("kernel32.dll")
+	procGetSystemPowerStat = kernel32.NewProc("GetSystemPowerStatus")
+)
+
+func getSystemPowerStatus(status *systemPowerStatus) error {
+	r1, _, err := procGetSystemPowerStat.Call(uintptr(unsafe.Pointer(status)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
+
+// WindowsProvider reads battery state via the GetSystemPowerStatus
+// Win32 API.
+type WindowsProvider struct{}
+
+func NewWindowsProvider() *WindowsProvider { return &WindowsProvider{} }
+
+func (p *WindowsProvider) Name() string { return "windows-powerstatus" }
+
+func (p *WindowsProvider) Read(ctx context.Context) (System, error) {
+	var status systemPowerStatus
+	if err := getSystemPowerStatus(&status); err != nil {
+		return System{}, fmt.Errorf("battery: GetSystemPowerStatus: %w", err)
+	}
+
+	info := BatteryInfo{ID: "System"}
+	if status.BatteryLifePercent != 255 { // 255 = unknown
+		info.Percent = int(status.BatteryLifePercent)
+	}
+
+	const acOnline = 1
+	info.IsCharging = status.ACLineStatus == acOnline
+
+	const batteryFlagCharging = 8
+	switch {
+	case info.IsCharging:
+		info.Status = "Charging"
+	case status.BatteryFlag&batteryFlagCharging != 0:
+		// ACLineStatus can lag the charging flag briefly after plugging in.
+		info.Status = "Charging"
+		info.IsCharging = true
+	default:
+		info.Status = "Discharging"
+	}
+
+	const unknownLifeTime = 0xFFFFFFFF
+	if status.BatteryLifeTime != unknownLifeTime {
+		mins := int(status.BatteryLifeTime) / 60
+		info.Remaining = fmt.Sprintf("%d:%02d remaining", mins/60, mins%60)
+	}
+
+	return NewSystem([]BatteryInfo{info}), nil
+}
+
+func init() {
+	Register(NewWindowsProvider())
+}
@@ -0,0 +1,94 @@
+// Package colorschemes provides named color palettes for the
+// chargetop TUI, selectable via --colorscheme or config, inspired by
+// gotop's colorscheme plugins.
+package colorschemes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Colorscheme holds every color the TUI needs. Fields map 1:1 to what
+// used to be hard-coded package-level vars in main.go.
+type Colorscheme struct {
+	Name     string
+	Bg       lipgloss.Color
+	Fg       lipgloss.Color
+	Subtle   lipgloss.Color
+	Accent   lipgloss.Color
+	Warning  lipgloss.Color
+	Critical lipgloss.Color
+	Success  lipgloss.Color
+}
+
+var builtins = map[string]Colorscheme{
+	"default": {
+		Name: "default", Bg: "0", Fg: "255", Subtle: "240",
+		Accent: "39", Warning: "208", Critical: "196", Success: "46",
+	},
+	"nord": {
+		Name: "nord", Bg: "#2e3440", Fg: "#eceff4", Subtle: "#4c566a",
+		Accent: "#88c0d0", Warning: "#ebcb8b", Critical: "#bf616a", Success: "#a3be8c",
+	},
+	"solarized-dark": {
+		Name: "solarized-dark", Bg: "#002b36", Fg: "#839496", Subtle: "#586e75",
+		Accent: "#268bd2", Warning: "#b58900", Critical: "#dc322f", Success: "#859900",
+	},
+	"dracula": {
+		Name: "dracula", Bg: "#282a36", Fg: "#f8f8f2", Subtle: "#6272a4",
+		Accent: "#8be9fd", Warning: "#ffb86c", Critical: "#ff5555", Success: "#50fa7b",
+	},
+	"monokai": {
+		Name: "monokai", Bg: "#272822", Fg: "#f8f8f2", Subtle: "#75715e",
+		Accent: "#66d9ef", Warning: "#fd971f", Critical: "#f92672", Success: "#a6e22e",
+	},
+}
+
+// Default is the built-in palette used when nothing else is configured.
+func Default() Colorscheme { return builtins["default"] }
+
+// Get looks up a built-in colorscheme by name.
+func Get(name string) (Colorscheme, bool) {
+	cs, ok := builtins[name]
+	return cs, ok
+}
+
+// Names lists the built-in colorscheme names.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for n := range builtins {
+		names = append(names, n)
+	}
+	return names
+}
+
+// LoadFile reads a user-supplied colorscheme - e.g. a mycolors.json
+// dropped into $XDG_CONFIG_HOME/chargetop/ - in the same JSON shape as
+// Colorscheme.
+func LoadFile(path string) (Colorscheme, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Colorscheme{}, err
+	}
+	var cs Colorscheme
+	if err := json.Unmarshal(b, &cs); err != nil {
+		return Colorscheme{}, fmt.Errorf("colorschemes: parsing %s: %w", path, err)
+	}
+	return cs, nil
+}
+
+// Resolve looks up name among the built-ins first, then falls back to
+// treating name as a path to a JSON colorscheme file.
+func Resolve(name string) (Colorscheme, error) {
+	if cs, ok := Get(name); ok {
+		return cs, nil
+	}
+	cs, err := LoadFile(name)
+	if err != nil {
+		return Colorscheme{}, fmt.Errorf("colorschemes: unknown colorscheme %q: %w", name, err)
+	}
+	return cs, nil
+}
@@ -0,0 +1,110 @@
+// Package config loads chargetop's on-disk settings: alert thresholds
+// and actions, plus colorscheme/layout preferences.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the on-disk chargetop configuration, loaded from
+// $XDG_CONFIG_HOME/chargetop/config.toml (or ~/.config/chargetop/config.toml
+// if XDG_CONFIG_HOME isn't set).
+type Config struct {
+	Alerts      AlertsConfig `toml:"alerts"`
+	Colorscheme string       `toml:"colorscheme"` // built-in name, or a path to a mycolors.json
+	Layout      string       `toml:"layout"`      // built-in preset name, or a literal layout DSL
+}
+
+// AlertsConfig configures the alerts subsystem (see package alerts).
+type AlertsConfig struct {
+	// ActionCooldown is the minimum time between repeated firings of a
+	// band's action while the battery stays inside that band.
+	ActionCooldown Duration `toml:"action_cooldown"`
+	Bands          []Band   `toml:"band"`
+}
+
+// Band is a single threshold/action pair, e.g. "low" at 15%.
+type Band struct {
+	Name      string `toml:"name"`
+	Threshold int    `toml:"threshold"` // percent
+	// Direction is "below" (fires while percent <= Threshold, e.g. low
+	// or critical) or "above" (fires while percent >= Threshold, e.g.
+	// the high/stop-charging-for-longevity reminder).
+	Direction string `toml:"direction"`
+	Action    Action `toml:"action"`
+}
+
+// Action describes what to do when a Band is entered.
+type Action struct {
+	Command string `toml:"command"` // shell command, run via `sh -c`
+	Notify  string `toml:"notify"`  // desktop notification body
+	Sound   string `toml:"sound"`   // built-in sound name, e.g. "default"
+}
+
+// Duration wraps time.Duration so it can be parsed from TOML strings
+// like "10m".
+type Duration struct{ time.Duration }
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// Path returns the config file location, honoring XDG_CONFIG_HOME.
+func Path() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "chargetop", "config.toml"), nil
+}
+
+// Load reads and parses the config file at Path(). A missing file is
+// not an error; it returns Default().
+func Load() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Default(), err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads and parses the config file at path, falling back to
+// Default() if it doesn't exist.
+func LoadFile(path string) (Config, error) {
+	cfg := Default()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Default returns the built-in thresholds: critical=5, low=15, and
+// high=80 (a reminder to unplug, for battery longevity).
+func Default() Config {
+	return Config{
+		Alerts: AlertsConfig{
+			ActionCooldown: Duration{10 * time.Minute},
+			Bands: []Band{
+				{Name: "critical", Threshold: 5, Direction: "below", Action: Action{Notify: "Battery critical"}},
+				{Name: "low", Threshold: 15, Direction: "below", Action: Action{Notify: "Battery low"}},
+				{Name: "high", Threshold: 80, Direction: "above", Action: Action{Notify: "Unplug to preserve battery health"}},
+			},
+		},
+	}
+}
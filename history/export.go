@@ -0,0 +1,78 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format is an export output format for Export.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatProm Format = "prom"
+)
+
+// Export writes samples to w in the given Format.
+func Export(w io.Writer, samples []Sample, format Format) error {
+	switch format {
+	case FormatCSV:
+		return exportCSV(w, samples)
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(samples)
+	case FormatProm:
+		return exportProm(w, samples)
+	default:
+		return fmt.Errorf("history: unknown export format %q", format)
+	}
+}
+
+func exportCSV(w io.Writer, samples []Sample) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	if err := cw.Write([]string{"time", "percent", "temperature_c", "wattage", "is_charging", "cycle_count", "health", "max_capacity", "design_capacity"}); err != nil {
+		return err
+	}
+	for _, s := range samples {
+		if err := cw.Write([]string{
+			s.Time.Format(time.RFC3339),
+			strconv.Itoa(s.Percent),
+			strconv.FormatFloat(s.Temperature, 'f', 1, 64),
+			strconv.Itoa(s.Wattage),
+			strconv.FormatBool(s.IsCharging),
+			strconv.Itoa(s.CycleCount),
+			s.Health,
+			strconv.Itoa(s.MaxCapacity),
+			strconv.Itoa(s.DesignCapacity),
+		}); err != nil {
+			return err
+		}
+	}
+	return cw.Error()
+}
+
+// exportProm writes one line per sample per metric, in Prometheus
+// text exposition format with an explicit millisecond timestamp -
+// suitable for `promtool check` or loading via remote_write tooling,
+// not direct scraping (see the -serve flag for that).
+func exportProm(w io.Writer, samples []Sample) error {
+	for _, s := range samples {
+		ts := s.Time.UnixMilli()
+		if _, err := fmt.Fprintf(w, "chargetop_percent %d %d\n", s.Percent, ts); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "chargetop_temperature_celsius %.1f %d\n", s.Temperature, ts); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "chargetop_wattage_watts %d %d\n", s.Wattage, ts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
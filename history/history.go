@@ -0,0 +1,242 @@
+// Package history persists battery samples to a rotating JSONL log
+// under the XDG state directory, giving users a longitudinal view of
+// battery health that tools like pmset or coconutBattery don't keep
+// for them.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aezizhu/chargetop/battery"
+)
+
+// Sample is one recorded BatteryInfo reading.
+type Sample struct {
+	Time        time.Time `json:"time"`
+	Percent     int       `json:"percent"`
+	Temperature float64   `json:"temperature"`
+	Wattage     int       `json:"wattage"`
+	IsCharging  bool      `json:"is_charging"`
+	CycleCount  int       `json:"cycle_count"`
+	Health      string    `json:"health"`
+
+	// MaxCapacity and DesignCapacity are copied from BatteryInfo so
+	// Trajectory can carry both capacity series, not just the collapsed
+	// percentage. On Linux they are the readings Health is derived
+	// from; on macOS MaxCapacity is the relative "MaxCapacity" value,
+	// while Health comes from AppleRawMaxCapacity, which is not stored.
+	MaxCapacity    int `json:"max_capacity"`
+	DesignCapacity int `json:"design_capacity"`
+}
+
+// SampleFrom builds a Sample from a live BatteryInfo reading taken at t.
+func SampleFrom(info battery.BatteryInfo, t time.Time) Sample {
+	return Sample{
+		Time:           t,
+		Percent:        info.Percent,
+		Temperature:    info.Temperature,
+		Wattage:        info.Wattage,
+		IsCharging:     info.IsCharging,
+		CycleCount:     info.CycleCount,
+		Health:         info.Health,
+		MaxCapacity:    info.MaxCapacity,
+		DesignCapacity: info.DesignCapacity,
+	}
+}
+
+// defaultRetention bounds how long the log keeps samples: whole days
+// older than this are dropped during rotate so a chargetop left
+// running for months doesn't grow the log forever.
+const defaultRetention = 90 * 24 * time.Hour
+
+// rotateEvery is how many Appends pass between rotations, so the
+// (cheap, file-count-bounded) rotate isn't run on every single tick.
+const rotateEvery = 3600
+
+// chunkSuffix is the extension on each day's chunk file.
+const chunkSuffix = ".jsonl"
+
+// Store is a rotating JSONL log of Samples, held as one file per
+// calendar day (UTC) under Dir - e.g. Dir/2024-03-01.jsonl. Chunking
+// by day means rotate() only ever has to delete whole expired files
+// instead of reading and rewriting the entire retained window, so its
+// cost scales with the number of days retained, not the number of
+// samples.
+type Store struct {
+	Dir string
+	// Retention overrides defaultRetention when non-zero.
+	Retention time.Duration
+
+	appends int
+}
+
+// DefaultDir returns the log directory under the XDG state directory
+// (XDG_STATE_HOME, or ~/.local/state if unset).
+func DefaultDir() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "chargetop", "history"), nil
+}
+
+// Open returns a Store backed by DefaultDir(), creating it if
+// necessary.
+func Open() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	st := &Store{Dir: dir}
+	_ = st.rotate() // drop anything left over Retention from a prior run; a failure here shouldn't block startup
+	return st, nil
+}
+
+// retention returns st.Retention, or defaultRetention if unset.
+func (st *Store) retention() time.Duration {
+	if st.Retention > 0 {
+		return st.Retention
+	}
+	return defaultRetention
+}
+
+// chunkPath returns the file that holds samples taken on t's calendar
+// day (UTC).
+func (st *Store) chunkPath(t time.Time) string {
+	return filepath.Join(st.Dir, t.UTC().Format("2006-01-02")+chunkSuffix)
+}
+
+// chunkDay parses the calendar day a chunk file covers from its name,
+// e.g. "2024-03-01.jsonl" -> 2024-03-01 UTC.
+func chunkDay(name string) (time.Time, bool) {
+	base := strings.TrimSuffix(name, chunkSuffix)
+	if base == name { // no chunkSuffix: not one of our files
+		return time.Time{}, false
+	}
+	day, err := time.Parse("2006-01-02", base)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// Append writes s to today's chunk file, rotating out expired days
+// every rotateEvery calls.
+func (st *Store) Append(s Sample) error {
+	f, err := os.OpenFile(st.chunkPath(s.Time), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(s); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	st.appends++
+	if st.appends%rotateEvery == 0 {
+		return st.rotate()
+	}
+	return nil
+}
+
+// rotate deletes whole chunk files whose calendar day falls entirely
+// before retention(); unlike a single-file log, this never needs to
+// read or rewrite the samples it keeps.
+func (st *Store) rotate() error {
+	entries, err := os.ReadDir(st.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cutoffDay := time.Now().UTC().Add(-st.retention()).Truncate(24 * time.Hour)
+	for _, e := range entries {
+		day, ok := chunkDay(e.Name())
+		if !ok || !day.Before(cutoffDay) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(st.Dir, e.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Since reads every Sample recorded within window of now.
+func (st *Store) Since(window time.Duration) ([]Sample, error) {
+	return st.SinceTime(time.Now().Add(-window))
+}
+
+// SinceTime reads every Sample recorded at or after cutoff, from
+// every chunk file that could hold one (cutoff's day onward).
+func (st *Store) SinceTime(cutoff time.Time) ([]Sample, error) {
+	entries, err := os.ReadDir(st.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cutoffDay := cutoff.UTC().Truncate(24 * time.Hour)
+	var names []string
+	for _, e := range entries {
+		if day, ok := chunkDay(e.Name()); ok && !day.Before(cutoffDay) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names) // "2006-01-02" chunk names sort chronologically
+
+	var out []Sample
+	for _, name := range names {
+		chunk, err := st.readChunk(filepath.Join(st.Dir, name), cutoff)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+	return out, nil
+}
+
+func (st *Store) readChunk(path string, cutoff time.Time) ([]Sample, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []Sample
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var s Sample
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			continue // skip a malformed line rather than failing the whole read
+		}
+		if !s.Time.Before(cutoff) {
+			out = append(out, s)
+		}
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,60 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aezizhu/chargetop/battery"
+)
+
+func TestSampleFromCapturesRawCapacities(t *testing.T) {
+	info := battery.BatteryInfo{Percent: 91, MaxCapacity: 5800, DesignCapacity: 6000, Health: "97%"}
+	s := SampleFrom(info, time.Now())
+	if s.MaxCapacity != 5800 || s.DesignCapacity != 6000 {
+		t.Errorf("MaxCapacity/DesignCapacity = %d/%d, want 5800/6000", s.MaxCapacity, s.DesignCapacity)
+	}
+}
+
+func TestStoreRotateDropsExpiredChunks(t *testing.T) {
+	st := &Store{Dir: t.TempDir(), Retention: 2 * 24 * time.Hour}
+
+	old := Sample{Time: time.Now().Add(-5 * 24 * time.Hour), Percent: 50}
+	recent := Sample{Time: time.Now(), Percent: 80}
+	for _, s := range []Sample{old, recent} {
+		if err := st.Append(s); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	if err := st.rotate(); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	got, err := st.SinceTime(time.Time{})
+	if err != nil {
+		t.Fatalf("SinceTime: %v", err)
+	}
+	if len(got) != 1 || got[0].Percent != 80 {
+		t.Fatalf("after rotate, samples = %+v, want only the recent 80%% sample", got)
+	}
+}
+
+func TestStoreSinceTimeSpansMultipleChunks(t *testing.T) {
+	st := &Store{Dir: t.TempDir()}
+
+	yesterday := Sample{Time: time.Now().Add(-24 * time.Hour), Percent: 60}
+	today := Sample{Time: time.Now(), Percent: 70}
+	for _, s := range []Sample{yesterday, today} {
+		if err := st.Append(s); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	got, err := st.SinceTime(time.Time{})
+	if err != nil {
+		t.Fatalf("SinceTime: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d samples across chunk files, want 2", len(got))
+	}
+}
@@ -0,0 +1,44 @@
+package history
+
+import "time"
+
+// WearPoint is one point in a battery's wear-over-time trajectory.
+type WearPoint struct {
+	Day            time.Time
+	Health         string
+	MaxCapacity    int
+	DesignCapacity int
+}
+
+// Trajectory buckets samples by calendar day and keeps the last
+// sample of each day, oldest first - a coarse "wear over time" series
+// suitable for a sparkline or small table. Samples must be in
+// chronological order (as Store.Since/SinceTime return them).
+//
+// MaxCapacity/DesignCapacity are carried through alongside the
+// collapsed Health string so a wear panel can show the two capacity
+// series against each other, not just one percentage per day (see
+// Sample for what MaxCapacity holds on each platform).
+func Trajectory(samples []Sample) []WearPoint {
+	byDay := map[string]Sample{}
+	var order []string
+	for _, s := range samples {
+		key := s.Time.Format("2006-01-02")
+		if _, ok := byDay[key]; !ok {
+			order = append(order, key)
+		}
+		byDay[key] = s // last sample of the day wins
+	}
+
+	points := make([]WearPoint, 0, len(order))
+	for _, key := range order {
+		s := byDay[key]
+		points = append(points, WearPoint{
+			Day:            s.Time,
+			Health:         s.Health,
+			MaxCapacity:    s.MaxCapacity,
+			DesignCapacity: s.DesignCapacity,
+		})
+	}
+	return points
+}
@@ -0,0 +1,115 @@
+// Package layout parses a small DSL describing which panes appear in
+// the chargetop TUI and in what arrangement, e.g.:
+//
+//	hero
+//	stats/2 history/1
+//	footer
+//
+// Each line is a row; panes within a row are separated by spaces and
+// optionally sized with a /N relative weight (default 1). model.View
+// walks the resulting Layout and renders each named pane with
+// lipgloss, inspired by gotop's layout spec files.
+package layout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pane names that the TUI knows how to render.
+const (
+	PaneHero    = "hero"
+	PaneStats   = "stats"
+	PaneHistory = "history"
+	PaneFooter  = "footer"
+)
+
+// knownPanes is PaneHero/PaneStats/PaneHistory/PaneFooter, used to
+// reject unrecognized pane names in Parse.
+var knownPanes = map[string]bool{
+	PaneHero:    true,
+	PaneStats:   true,
+	PaneHistory: true,
+	PaneFooter:  true,
+}
+
+// Cell is one pane within a Row, with a relative weight for sizing.
+type Cell struct {
+	Pane   string
+	Weight int
+}
+
+// Row is one line of the layout: one or more Cells rendered side by side.
+type Row struct {
+	Cells []Cell
+}
+
+// Layout is a parsed DSL: rows rendered top to bottom.
+type Layout struct {
+	Rows []Row
+}
+
+// Parse parses the layout DSL described in the package doc comment.
+func Parse(src string) (Layout, error) {
+	var lay Layout
+	for i, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var row Row
+		for _, tok := range strings.Fields(line) {
+			cell, err := parseCell(tok)
+			if err != nil {
+				return Layout{}, fmt.Errorf("layout: line %d: %w", i+1, err)
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		lay.Rows = append(lay.Rows, row)
+	}
+	if len(lay.Rows) == 0 {
+		return Layout{}, fmt.Errorf("layout: empty layout")
+	}
+	return lay, nil
+}
+
+func parseCell(tok string) (Cell, error) {
+	pane, weightStr, hasWeight := strings.Cut(tok, "/")
+	weight := 1
+	if hasWeight {
+		w, err := strconv.Atoi(weightStr)
+		if err != nil || w <= 0 {
+			return Cell{}, fmt.Errorf("invalid weight in %q", tok)
+		}
+		weight = w
+	}
+	if !knownPanes[pane] {
+		return Cell{}, fmt.Errorf("unknown pane %q", pane)
+	}
+	return Cell{Pane: pane, Weight: weight}, nil
+}
+
+// Presets mirror gotop's defaultUI/minimalUI/batteryUI constants: named
+// shortcuts for --layout so users don't have to write the DSL by hand.
+var Presets = map[string]string{
+	"full":    "hero\nstats/2 history/1\nfooter",
+	"minimal": "hero\nfooter",
+	"hero":    "hero",
+	"log":     "history\nfooter",
+}
+
+// Default is the layout used when nothing else is configured.
+func Default() Layout {
+	lay, _ := Parse(Presets["full"])
+	return lay
+}
+
+// Resolve looks up name among the built-in presets first, then falls
+// back to parsing name itself as a literal DSL layout.
+func Resolve(name string) (Layout, error) {
+	if src, ok := Presets[name]; ok {
+		return Parse(src)
+	}
+	return Parse(name)
+}
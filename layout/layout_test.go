@@ -0,0 +1,27 @@
+package layout
+
+import "testing"
+
+func TestParseRejectsUnknownPane(t *testing.T) {
+	_, err := Parse("hero\nbogus/1 footer")
+	if err == nil {
+		t.Fatal("Parse: want error for unknown pane name, got nil")
+	}
+}
+
+func TestResolveRejectsUnknownPane(t *testing.T) {
+	_, err := Resolve("bogus")
+	if err == nil {
+		t.Fatal("Resolve: want error for unknown pane name, got nil")
+	}
+}
+
+func TestParseKnownPanes(t *testing.T) {
+	lay, err := Parse(Presets["full"])
+	if err != nil {
+		t.Fatalf("Parse(full): %v", err)
+	}
+	if len(lay.Rows) != 3 {
+		t.Fatalf("Rows = %d, want 3", len(lay.Rows))
+	}
+}
@@ -1,63 +1,70 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"net/http"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/aezizhu/chargetop/alerts"
 	"github.com/aezizhu/chargetop/battery"
+	"github.com/aezizhu/chargetop/colorschemes"
+	"github.com/aezizhu/chargetop/config"
+	"github.com/aezizhu/chargetop/history"
+	"github.com/aezizhu/chargetop/layout"
+	"github.com/aezizhu/chargetop/metrics"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-
-	// "github.com/charmbracelet/bubbles/sparkline"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Styles
-var (
-	// Apple-esque Palette
-	bg       = lipgloss.Color("0")   // Pitch black or terminal default
-	fg       = lipgloss.Color("255") // White
-	subtle   = lipgloss.Color("240") // Dark Grey
-	accent   = lipgloss.Color("39")  // Dodson Blue (Classic Apple)
-	warning  = lipgloss.Color("208") // Orange
-	critical = lipgloss.Color("196") // Red
-	success  = lipgloss.Color("46")  // Green
-
-	appStyle = lipgloss.NewStyle().
+// styles builds the lipgloss styles used throughout View from the
+// active colorscheme. Colors used to be hard-coded package-level vars;
+// now they come from m.colors so --colorscheme/config can change them
+// at startup.
+type styles struct {
+	app      lipgloss.Style
+	mainText lipgloss.Style
+	label    lipgloss.Style
+	value    lipgloss.Style
+	subtleFg lipgloss.Style
+}
+
+func newStyles(c colorschemes.Colorscheme) styles {
+	return styles{
+		app: lipgloss.NewStyle().
 			Padding(1, 4).
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(subtle).
-			Align(lipgloss.Center)
-
-	mainTextStyle = lipgloss.NewStyle().
-			Foreground(fg).
-			Bold(true)
-
-	labelStyle = lipgloss.NewStyle().
-			Foreground(subtle).
-			Width(25) // Fixed width for alignment
-
-	valueStyle = lipgloss.NewStyle().
-			Foreground(fg).
-			Bold(true)
-)
+			BorderForeground(c.Subtle).
+			Align(lipgloss.Center),
+		mainText: lipgloss.NewStyle().Foreground(c.Fg).Bold(true),
+		label:    lipgloss.NewStyle().Foreground(c.Subtle).Width(25), // Fixed width for alignment
+		value:    lipgloss.NewStyle().Foreground(c.Fg).Bold(true),
+		subtleFg: lipgloss.NewStyle().Foreground(c.Subtle),
+	}
+}
 
 // Keys
 type keyMap struct {
 	Quit    key.Binding
 	Refresh key.Binding
 	Help    key.Binding
+	History key.Binding
+	Left    key.Binding
+	Right   key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Refresh, k.Quit}
+	return []key.Binding{k.Help, k.Refresh, k.History, k.Left, k.Right, k.Quit}
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Help, k.Refresh, k.Quit},
+		{k.Help, k.Refresh, k.History, k.Left, k.Right, k.Quit},
 	}
 }
 
@@ -74,20 +81,42 @@ var keys = keyMap{
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
 	),
+	History: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "toggle history view"),
+	),
+	Left: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "prev battery"),
+	),
+	Right: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "next battery"),
+	),
 }
 
 // Messages
 type tickMsg time.Time
 type batteryMsg struct {
-	info battery.BatteryInfo
-	err  error
+	sys battery.System
+	err error
 }
 
 type model struct {
-	info battery.BatteryInfo
+	sys     battery.System
+	focused int // index into sys.Batteries of the battery shown in hero/stats
 
-	// sparkModel sparkline.Model
-	history []int
+	history     []int     // last 60 aggregate percent samples, for the in-TUI sparkline
+	tempHistory []float64 // last 60 aggregate temperature samples, same cadence
+
+	cfg      config.Config
+	alerts   *alerts.Evaluator
+	histLog  *history.Store // nil if the history log couldn't be opened
+	showHist bool
+
+	colors colorschemes.Colorscheme
+	sty    styles
+	lay    layout.Layout
 
 	help help.Model
 	keys keyMap
@@ -98,16 +127,47 @@ type model struct {
 	now    time.Time
 }
 
-func initialModel() model {
+// initialModel builds the starting model. colorschemeName/layoutName
+// come from --colorscheme/--layout (falling back to config.toml, then
+// the built-in defaults) and are resolved here; a name that does not
+// resolve falls back to the built-in default without an error.
+func initialModel(colorschemeName, layoutName string) model {
 	// Initial fetch is synchronous to populate first frame, or we can start empty
-	b, _ := battery.GetBatteryInfo()
+	sys, _ := battery.GetSystem()
+	agg := sys.Aggregate()
+
+	cfg, _ := config.Load() // missing/invalid config falls back to config.Default()
+	histLog, _ := history.Open()
+
+	if colorschemeName == "" {
+		colorschemeName = cfg.Colorscheme
+	}
+	colors, err := colorschemes.Resolve(colorschemeName)
+	if err != nil {
+		colors = colorschemes.Default()
+	}
+
+	if layoutName == "" {
+		layoutName = cfg.Layout
+	}
+	lay, err := layout.Resolve(layoutName)
+	if err != nil {
+		lay = layout.Default()
+	}
 
 	return model{
-		info:    b,
-		history: []int{b.Percent},
-		help:    help.New(),
-		keys:    keys,
-		now:     time.Now(),
+		sys:         sys,
+		history:     []int{agg.Percent},
+		tempHistory: []float64{agg.Temperature},
+		cfg:         cfg,
+		alerts:      alerts.New(cfg.Alerts),
+		histLog:     histLog,
+		colors:      colors,
+		sty:         newStyles(colors),
+		lay:         lay,
+		help:        help.New(),
+		keys:        keys,
+		now:         time.Now(),
 	}
 }
 
@@ -128,6 +188,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, fetchBatteryCmd()
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
+		case key.Matches(msg, m.keys.History):
+			m.showHist = !m.showHist
+		case key.Matches(msg, m.keys.Left):
+			m.focused = prevBattery(m.focused, len(m.sys.Batteries))
+		case key.Matches(msg, m.keys.Right):
+			m.focused = nextBattery(m.focused, len(m.sys.Batteries))
 		}
 
 	case tea.WindowSizeMsg:
@@ -144,111 +210,356 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			m.info = msg.info
+			m.sys = msg.sys
 			m.err = nil
-			m.history = append(m.history, msg.info.Percent)
+			if m.focused >= len(m.sys.Batteries) {
+				m.focused = 0
+			}
+
+			agg := m.sys.Aggregate()
+			m.history = append(m.history, agg.Percent)
 			if len(m.history) > 60 {
 				m.history = m.history[1:]
 			}
+			m.tempHistory = append(m.tempHistory, agg.Temperature)
+			if len(m.tempHistory) > 60 {
+				m.tempHistory = m.tempHistory[1:]
+			}
+
+			fired := m.alerts.Sample(agg)
+			return m, tea.Batch(alertsCmd(m.alerts, fired), appendHistoryCmd(m.histLog, history.SampleFrom(agg, time.Now())))
 		}
 	}
 
 	return m, nil
 }
 
+// alertsCmd runs fired bands' actions (shell command, notification,
+// sound) off the Update goroutine - Evaluator.Fire can shell out and
+// block, and Bubble Tea runs Update for one Msg at a time, so firing
+// inline would wedge the whole TUI (including the quit keybinding)
+// until a slow or hung hook returns. Matches the fetchBatteryCmd
+// pattern already used for battery I/O in this file.
+func alertsCmd(e *alerts.Evaluator, bands []config.Band) tea.Cmd {
+	if len(bands) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for _, b := range bands {
+			e.Fire(context.Background(), b)
+		}
+		return nil
+	}
+}
+
+// appendHistoryCmd appends s to log off the Update goroutine, for the
+// same reason as alertsCmd: a blocking file open/write/close once per
+// tick shouldn't stall rendering or input handling.
+func appendHistoryCmd(log *history.Store, s history.Sample) tea.Cmd {
+	if log == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = log.Append(s)
+		return nil
+	}
+}
+
 func (m model) View() string {
 	if m.err != nil {
 		return fmt.Sprintf("\n  Error: %v\n", m.err)
 	}
 
-	// Dynamic Status Color
-	statusColor := success
-	if m.info.Percent < 15 {
-		statusColor = critical
-	} else if m.info.Percent < 30 {
-		statusColor = warning
+	if m.showHist {
+		return m.historyView()
+	}
+
+	panes := map[string]string{
+		layout.PaneHero:    m.heroPane(),
+		layout.PaneStats:   m.statsPane(),
+		layout.PaneHistory: m.historyPane(),
+		layout.PaneFooter:  m.footerPane(),
+	}
+
+	return m.sty.app.Render(m.renderLayout(panes))
+}
+
+// focusedBattery returns the battery currently shown in the hero/stats
+// panes. On a single-battery system this is simply that battery, so
+// behavior is unchanged from before multi-battery support existed.
+func (m model) focusedBattery() battery.BatteryInfo {
+	if len(m.sys.Batteries) == 0 {
+		return m.sys.Aggregate()
+	}
+	idx := m.focused
+	if idx < 0 || idx >= len(m.sys.Batteries) {
+		idx = 0
+	}
+	return m.sys.Batteries[idx]
+}
+
+func prevBattery(i, n int) int {
+	if n == 0 {
+		return 0
 	}
+	return (i - 1 + n) % n
+}
+
+func nextBattery(i, n int) int {
+	if n == 0 {
+		return 0
+	}
+	return (i + 1) % n
+}
+
+// tabsPane renders a battery-selector tab strip, highlighting the
+// focused battery. It's empty on a single-battery system, so the hero
+// pane's layout is identical to before multi-battery support.
+func (m model) tabsPane() string {
+	if len(m.sys.Batteries) < 2 {
+		return ""
+	}
+
+	var tabs []string
+	for i, b := range m.sys.Batteries {
+		label := fmt.Sprintf(" %s ", b.ID)
+		if i == m.focused {
+			tabs = append(tabs, lipgloss.NewStyle().Foreground(m.colors.Accent).Bold(true).Render(label))
+		} else {
+			tabs = append(tabs, m.sty.subtleFg.Render(label))
+		}
+	}
+	return lipgloss.NewStyle().MarginBottom(1).Render(lipgloss.JoinHorizontal(lipgloss.Center, tabs...))
+}
+
+// heroPane renders the battery tab strip (if more than one battery),
+// the big percentage, status icon, and remaining time for the focused
+// battery.
+func (m model) heroPane() string {
+	info := m.focusedBattery()
+	statusColor := m.statusColor(info)
 
-	// --- 1. The Big Percentage (The Hero) ---
 	pctBig := lipgloss.NewStyle().
 		Foreground(statusColor).
 		Bold(true).
-		Render(fmt.Sprintf("%d%%", m.info.Percent))
+		Render(fmt.Sprintf("%d%%", info.Percent))
 
 	statusIcon := "⚡"
-	if !m.info.IsCharging {
+	if !info.IsCharging {
 		statusIcon = "🔋"
 	}
 
-	heroSection := lipgloss.JoinVertical(lipgloss.Center,
-		lipgloss.NewStyle().Foreground(subtle).Render(strings.ToUpper(m.info.Status)),
+	var parts []string
+	if tabs := m.tabsPane(); tabs != "" {
+		parts = append(parts, tabs)
+	}
+	parts = append(parts,
+		m.sty.subtleFg.Render(strings.ToUpper(info.Status)),
 		lipgloss.NewStyle().Margin(1, 0).Render(
 			lipgloss.JoinHorizontal(lipgloss.Center,
 				lipgloss.NewStyle().Foreground(statusColor).MarginRight(1).Render(statusIcon),
 				pctBig,
 			),
 		),
-		lipgloss.NewStyle().Foreground(subtle).Render(m.info.Remaining),
+		m.sty.subtleFg.Render(info.Remaining),
 	)
+	return lipgloss.JoinVertical(lipgloss.Center, parts...)
+}
+
+// statsPane renders the detail grid (cycle count, temperature, etc) for
+// the focused battery.
+func (m model) statsPane() string {
+	info := m.focusedBattery()
 
-	// --- 2. The Grid (The Details) ---
 	row := func(label, value string) string {
 		return lipgloss.JoinHorizontal(lipgloss.Left,
-			labelStyle.Render(label),
-			valueStyle.Render(value),
+			m.sty.label.Render(label),
+			m.sty.value.Render(value),
 		)
 	}
 
-	// Minimalist Divider
-	divider := lipgloss.NewStyle().
-		Foreground(subtle).
-		Margin(1, 0).
-		Render("───────────────────────────────────────")
-
-	safeCycle := fmt.Sprintf("%d", m.info.CycleCount)
-	if m.info.CycleCount == 0 {
+	safeCycle := fmt.Sprintf("%d", info.CycleCount)
+	if info.CycleCount == 0 {
 		safeCycle = "..."
 	}
 
-	safeWattage := fmt.Sprintf("%dW", m.info.Wattage)
-	if m.info.Wattage == 0 {
+	safeWattage := fmt.Sprintf("%dW", info.Wattage)
+	if info.Wattage == 0 {
 		safeWattage = "..."
 	}
 
-	statsSection := lipgloss.JoinVertical(lipgloss.Left,
-		row("Condition", m.info.Condition),
+	return lipgloss.JoinVertical(lipgloss.Left,
+		row("Condition", info.Condition),
 		row("Cycle Count", safeCycle),
-		row("Max Capacity", m.info.MaxCapacity),
-		row("Temperature", fmt.Sprintf("%.1f°C", m.info.Temperature)),
+		row("Max Capacity", fmt.Sprintf("%d", info.MaxCapacity)),
+		row("Temperature", fmt.Sprintf("%.1f°C", info.Temperature)),
 		lipgloss.NewStyle().Height(1).Render(""),
 		row("Power Source", "USB-C Power Type"),
 		row("Wattage Input", safeWattage),
-		row("Serial Number", m.info.Serial),
+		row("Serial Number", info.Serial),
 	)
+}
 
-	// Combine
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		heroSection,
-		divider,
-		statsSection,
+// historyPane renders a compact percent sparkline for layouts that
+// include a "history" pane alongside hero/stats; the full-screen
+// history view (toggled with 'v') has its own historyView.
+func (m model) historyPane() string {
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.sty.label.Render("Percent"),
+		lipgloss.NewStyle().Foreground(m.colors.Accent).Render(sparkline(m.history, 100)),
 	)
+}
 
-	// Footer (Help + Clock)
+// footerPane renders the help line and clock.
+func (m model) footerPane() string {
 	helpView := m.help.View(m.keys)
-	clockView := lipgloss.NewStyle().Foreground(subtle).Render(m.now.Format("15:04:05"))
+	clockView := m.sty.subtleFg.Render(m.now.Format("15:04:05"))
 
 	footerRow := lipgloss.JoinHorizontal(lipgloss.Center,
 		helpView,
-		lipgloss.NewStyle().Foreground(subtle).Margin(0, 2).Render("•"),
+		m.sty.subtleFg.Margin(0, 2).Render("•"),
 		clockView,
 	)
 
-	footer := lipgloss.NewStyle().Foreground(subtle).MarginTop(2).Render(footerRow)
+	return m.sty.subtleFg.MarginTop(2).Render(footerRow)
+}
+
+// divider renders the horizontal rule used between panes.
+func (m model) divider() string {
+	return m.sty.subtleFg.Margin(1, 0).Render("───────────────────────────────────────")
+}
+
+// renderLayout walks m.lay, joining each row's panes horizontally
+// (weighted by m.width when known) and stacking rows vertically, with
+// a divider between consecutive rows.
+func (m model) renderLayout(panes map[string]string) string {
+	var rows []string
+	for i, row := range m.lay.Rows {
+		if i > 0 {
+			rows = append(rows, m.divider())
+		}
+
+		if len(row.Cells) == 1 {
+			rows = append(rows, panes[row.Cells[0].Pane])
+			continue
+		}
+
+		totalWeight := 0
+		for _, c := range row.Cells {
+			totalWeight += c.Weight
+		}
+
+		var cells []string
+		for _, c := range row.Cells {
+			content := panes[c.Pane]
+			if m.width > 0 {
+				w := m.width * c.Weight / totalWeight
+				content = lipgloss.NewStyle().Width(w).Render(content)
+			}
+			cells = append(cells, content)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Center, rows...)
+}
+
+// statusColor picks the hero percentage's color from the configured
+// alert bands: a "below" band crossed renders critical if named
+// "critical" and warning otherwise; an "above" band crossed (e.g. the
+// high/stop-charging reminder) also renders warning.
+func (m model) statusColor(info battery.BatteryInfo) lipgloss.Color {
+	color := m.colors.Success
+	for _, b := range m.cfg.Alerts.Bands {
+		switch b.Direction {
+		case "above":
+			if info.Percent >= b.Threshold {
+				color = m.colors.Warning
+			}
+		default:
+			if info.Percent <= b.Threshold {
+				if b.Name == "critical" {
+					return m.colors.Critical
+				}
+				color = m.colors.Warning
+			}
+		}
+	}
+	return color
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a single line of block characters
+// scaled against max.
+func sparkline(values []int, max int) string {
+	if max <= 0 {
+		max = 1
+	}
+	var b strings.Builder
+	for _, v := range values {
+		idx := v * (len(sparkBlocks) - 1) / max
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(sparkBlocks) {
+			idx = len(sparkBlocks) - 1
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// historyView renders the in-TUI sparkline of recent percent/temperature
+// samples plus a day-by-day wear trajectory pulled from the on-disk log.
+func (m model) historyView() string {
+	pctLine := lipgloss.NewStyle().Foreground(m.colors.Accent).Render(sparkline(m.history, 100))
+
+	tempInts := make([]int, len(m.tempHistory))
+	for i, t := range m.tempHistory {
+		tempInts[i] = int(t) // degrees Celsius, whole-number resolution is enough for a sparkline
+	}
+	tempLine := lipgloss.NewStyle().Foreground(m.colors.Warning).Render(sparkline(tempInts, 60))
+
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		m.sty.label.Render("Percent (last "+fmt.Sprintf("%d", len(m.history))+" samples)"),
+		pctLine,
+		lipgloss.NewStyle().Height(1).Render(""),
+		m.sty.label.Render("Temperature"),
+		tempLine,
+	)
+
+	wear := "no history log available"
+	if m.histLog != nil {
+		if samples, err := m.histLog.Since(7 * 24 * time.Hour); err == nil {
+			var rows []string
+			for _, p := range history.Trajectory(samples) {
+				if p.DesignCapacity > 0 {
+					rows = append(rows, fmt.Sprintf("%s  health %s  (%d/%d)", p.Day.Format("Jan 2"), p.Health, p.MaxCapacity, p.DesignCapacity))
+				} else {
+					rows = append(rows, fmt.Sprintf("%s  health %s", p.Day.Format("Jan 2"), p.Health))
+				}
+			}
+			if len(rows) > 0 {
+				wear = strings.Join(rows, "\n")
+			} else {
+				wear = "not enough history yet"
+			}
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		m.sty.mainText.Render("History"),
+		m.divider(),
+		body,
+		m.divider(),
+		m.sty.subtleFg.Render("Wear trajectory (last 7 days)"),
+		wear,
+	)
 
-	return appStyle.Render(
+	return m.sty.app.Render(
 		lipgloss.JoinVertical(lipgloss.Center,
 			content,
-			footer,
+			m.sty.subtleFg.MarginTop(2).Render(m.help.View(m.keys)),
 		),
 	)
 }
@@ -261,14 +572,72 @@ func tickCmd() tea.Cmd {
 
 func fetchBatteryCmd() tea.Cmd {
 	return func() tea.Msg {
-		info, err := battery.GetBatteryInfo()
-		return batteryMsg{info: info, err: err}
+		sys, err := battery.GetSystem()
+		return batteryMsg{sys: sys, err: err}
 	}
 }
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	colorscheme := flag.String("colorscheme", "", fmt.Sprintf("colorscheme to use: %s, or a path to a mycolors.json (default from config, else \"default\")", strings.Join(colorschemes.Names(), ", ")))
+	layoutPreset := flag.String("layout", "", "layout preset (minimal, full, hero, log) or a literal layout DSL (default from config, else \"full\")")
+	serveAddr := flag.String("serve", "", "address to serve Prometheus /metrics on, e.g. :9101")
+	noTUI := flag.Bool("no-tui", false, "run headless, without the TUI (use alongside -serve)")
+	flag.Parse()
+
+	if *serveAddr != "" {
+		go serveMetrics(*serveAddr)
+	}
+
+	if *noTUI {
+		if *serveAddr == "" {
+			fmt.Fprintln(os.Stderr, "Error: --no-tui requires -serve")
+			os.Exit(1)
+		}
+		select {} // the metrics server runs in the background goroutine started above
+	}
+
+	p := tea.NewProgram(initialModel(*colorscheme, *layoutPreset), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v", err)
 	}
 }
+
+// serveMetrics runs the Prometheus /metrics endpoint, reusing
+// battery.Current() so the TUI and the scrape endpoint share one
+// source of truth.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.NewHandler(battery.Current()))
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		fmt.Fprintln(os.Stderr, "metrics server:", err)
+	}
+}
+
+// runExport implements `chargetop export --format {csv,json,prom} --since 24h`,
+// dumping the on-disk history log built up by the TUI.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "csv", "output format: csv, json, or prom")
+	since := fs.Duration("since", 24*time.Hour, "how far back to export, e.g. 24h")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log, err := history.Open()
+	if err != nil {
+		return err
+	}
+	samples, err := log.Since(*since)
+	if err != nil {
+		return err
+	}
+	return history.Export(os.Stdout, samples, history.Format(*format))
+}
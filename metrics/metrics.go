@@ -0,0 +1,91 @@
+// Package metrics exposes battery telemetry in Prometheus text
+// exposition format over HTTP, so chargetop can be scraped like a
+// node-exporter source for Grafana dashboards watching laptop fleets -
+// mirroring gotop's embedded VictoriaMetrics integration.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/aezizhu/chargetop/battery"
+)
+
+// Handler serves /metrics, sampling the given Provider on every
+// request so there's a single source of truth with the TUI.
+type Handler struct {
+	Provider battery.Provider
+	Hostname string
+}
+
+// NewHandler builds a Handler backed by provider.
+func NewHandler(provider battery.Provider) *Handler {
+	host, _ := os.Hostname()
+	return &Handler{Provider: provider, Hostname: host}
+}
+
+// sample is one battery reading labeled with its id, for one row of a
+// metric family.
+type sample struct {
+	id   string
+	info battery.BatteryInfo
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.Provider == nil {
+		http.Error(w, "chargetop: no battery provider registered for this platform", http.StatusInternalServerError)
+		return
+	}
+
+	sys, err := h.Provider.Read(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("chargetop: reading battery: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// One sample per physical battery, plus a "system" sample for the
+	// weighted aggregate - on a single-battery machine these coincide.
+	samples := make([]sample, 0, len(sys.Batteries)+1)
+	for _, info := range sys.Batteries {
+		samples = append(samples, sample{id: info.ID, info: info})
+	}
+	samples = append(samples, sample{id: "system", info: sys.Aggregate()})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	h.family(w, "chargetop_percent", "Battery charge percent.", samples, func(i battery.BatteryInfo) float64 { return float64(i.Percent) })
+	h.family(w, "chargetop_temperature_celsius", "Battery temperature in Celsius.", samples, func(i battery.BatteryInfo) float64 { return i.Temperature })
+	h.family(w, "chargetop_cycle_count", "Battery charge cycle count.", samples, func(i battery.BatteryInfo) float64 { return float64(i.CycleCount) })
+	h.family(w, "chargetop_wattage_watts", "Power adapter wattage.", samples, func(i battery.BatteryInfo) float64 { return float64(i.Wattage) })
+	h.family(w, "chargetop_health_ratio", "Battery health as a 0-1 ratio of raw max to design capacity.", samples, func(i battery.BatteryInfo) float64 { return healthRatio(i.Health) })
+	h.family(w, "chargetop_is_charging", "1 if the battery is charging, 0 otherwise.", samples, func(i battery.BatteryInfo) float64 {
+		if i.IsCharging {
+			return 1
+		}
+		return 0
+	})
+}
+
+// family writes one Prometheus gauge family: a single HELP/TYPE header
+// followed by one sample line per battery, labeled with id, serial and
+// hostname.
+func (h *Handler) family(w http.ResponseWriter, name, help string, samples []sample, value func(battery.BatteryInfo) float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	for _, s := range samples {
+		serial := s.info.Serial
+		if serial == "" {
+			serial = "unknown"
+		}
+		fmt.Fprintf(w, "%s{id=%q,serial=%q,hostname=%q} %v\n", name, s.id, serial, h.Hostname, value(s.info))
+	}
+}
+
+// healthRatio parses a BatteryInfo.Health string like "93%" into a 0-1
+// ratio, or 0 if Health is empty/unparseable.
+func healthRatio(health string) float64 {
+	var pct float64
+	if _, err := fmt.Sscanf(health, "%f%%", &pct); err != nil {
+		return 0
+	}
+	return pct / 100
+}